@@ -33,5 +33,9 @@ func apiFlags() []cli.Flag {
 			DefaultText: consts.HertzRepoDefaultUrl,
 			Usage:       "Specify the url of the hertz repository you want",
 		},
+		&cli.BoolFlag{
+			Name:  consts.Json,
+			Usage: "Print routers as json.",
+		},
 	}
 }
@@ -39,6 +39,8 @@ func modelFlags() []cli.Flag {
 			}
 			return nil
 		}},
+		&cli.StringFlag{Name: consts.Schema, Usage: "Specify the database schema to introspect, only used by postgres. (default is the current schema of the dsn)", Value: "", DefaultText: ""},
+		&cli.BoolFlag{Name: consts.Partitions, Usage: "Specify generate models for partitions of partitioned tables, only used by postgres", Value: false, DefaultText: "false"},
 		&cli.StringFlag{Name: consts.OutDir, Usage: "Specify output directory", Value: consts.DefaultDbOutDir, DefaultText: consts.DefaultDbOutDir},
 		&cli.StringFlag{Name: consts.OutFile, Usage: "Specify output filename", Value: consts.DefaultDbOutFile, DefaultText: consts.DefaultDbOutFile},
 		&cli.StringSliceFlag{Name: consts.Tables, Usage: "Specify databases tables"},
@@ -35,5 +35,14 @@ func serverFlags() []cli.Flag {
 		&cli.StringSliceFlag{Name: consts.Pass, Usage: "Pass param to hz or Kitex."},
 		&cli.BoolFlag{Name: consts.Verbose, Usage: "Turn on verbose mode."},
 		&cli.BoolFlag{Name: consts.HexTag, Usage: "Add HTTP listen for Kitex.", Destination: &globalArgs.Hex},
+		&cli.StringFlag{Name: consts.Deploy, Usage: "Specify the deploy scaffold to generate alongside the server. (docker or k8s)"},
+		&cli.StringFlag{Name: consts.DeployTemplate, Usage: "Specify the deploy template path. Same as `--template`, git templates are supported.", Destination: &globalArgs.ServerArgument.DeployTemplate},
+		&cli.StringSliceFlag{Name: consts.DeployWith, Usage: "Specify the middlewares wired into the deploy scaffold. (mysql or redis)"},
+		&cli.BoolFlag{Name: consts.Force, Usage: "Overwrite the existing deploy files.", Destination: &globalArgs.ServerArgument.Force},
+		&cli.IntFlag{Name: consts.Replicas, Usage: "Specify the replicas of the k8s deployment.", Value: 1, Destination: &globalArgs.ServerArgument.Replicas},
+		&cli.IntFlag{Name: consts.MaxReplicas, Usage: "Specify the max replicas of the k8s hpa.", Value: 3, Destination: &globalArgs.ServerArgument.MaxReplicas},
+		&cli.StringFlag{Name: consts.Image, Usage: "Specify the image of the k8s deployment, default is {service}:latest.", Destination: &globalArgs.ServerArgument.Image},
+		&cli.StringFlag{Name: consts.CPURequest, Usage: "Specify the cpu request of the k8s deployment.", Value: "100m", Destination: &globalArgs.ServerArgument.CPURequest},
+		&cli.StringFlag{Name: consts.MemoryRequest, Usage: "Specify the memory request of the k8s deployment.", Value: "128Mi", Destination: &globalArgs.ServerArgument.MemoryRequest},
 	}
 }
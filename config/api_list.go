@@ -24,6 +24,7 @@ import (
 type ApiArgument struct {
 	ProjectPath  string
 	HertzRepoUrl string
+	Json         bool
 }
 
 func NewApiArgument() *ApiArgument {
@@ -33,5 +34,6 @@ func NewApiArgument() *ApiArgument {
 func (c *ApiArgument) ParseCli(ctx *cli.Context) error {
 	c.ProjectPath = ctx.String(consts.ProjectPath)
 	c.HertzRepoUrl = ctx.String(consts.HertzRepoUrl)
+	c.Json = ctx.Bool(consts.Json)
 	return nil
 }
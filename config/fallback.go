@@ -18,6 +18,8 @@ package config
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/cloudwego/cwgo/pkg/consts"
 	"github.com/urfave/cli/v2"
@@ -25,7 +27,8 @@ import (
 
 type FallbackArgument struct {
 	ToolType consts.ToolType
-	Args     []string
+	Args     []string // tool type followed by the tool flags, in original order
+	Verbose  bool     // verbose flag of the tool, e.g. kitex -v
 }
 
 func NewFallbackArgument() *FallbackArgument {
@@ -38,16 +41,62 @@ func (c *FallbackArgument) ParseCli(ctx *cli.Context) error {
 		return fmt.Errorf("please input tool type")
 	}
 
-	c.ToolType = consts.ToolType(args[0])
+	// flags are not parsed by cli, so the help flag of fallback itself is handled here,
+	// and ToolType is left empty to run nothing
+	if isHelpFlag(args[0]) {
+		cli.HelpPrinter(ctx.App.Writer, cli.CommandHelpTemplate, ctx.Command)
+		return nil
+	}
+
 	switch consts.ToolType(args[0]) {
 	case consts.Hz:
 		c.ToolType = consts.Hz
 	case consts.KitexTool:
 		c.ToolType = consts.KitexTool
 	default:
-		return fmt.Errorf("tool type is not supported")
+		return fmt.Errorf("tool type %s is not supported (support %s || %s for now)", args[0], consts.Hz, consts.KitexTool)
 	}
 
 	c.Args = args
+	c.Verbose = false
+	for _, arg := range args[1:] {
+		if arg == "--" {
+			break
+		}
+		if isVerboseFlag(c.ToolType, arg) {
+			c.Verbose = true
+		}
+	}
 	return nil
 }
+
+// isHelpFlag reports whether arg is -h or --help.
+func isHelpFlag(arg string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	name := strings.TrimLeft(arg, "-")
+	return name == "h" || name == "help"
+}
+
+// verboseFlagNames are the names of the verbose flag of each tool,
+// -v of hz prints its version instead.
+var verboseFlagNames = map[consts.ToolType][]string{
+	consts.KitexTool: {"v", consts.Verbose},
+	consts.Hz:        {consts.Verbose, "vv"},
+}
+
+// isVerboseFlag reports whether arg turns on the verbose mode of tool.
+func isVerboseFlag(tool consts.ToolType, arg string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	for _, verbose := range verboseFlagNames[tool] {
+		if name == verbose {
+			on, err := strconv.ParseBool(value)
+			return !hasValue || (err == nil && on)
+		}
+	}
+	return false
+}
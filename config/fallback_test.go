@@ -0,0 +1,155 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/cwgo/pkg/consts"
+	"github.com/urfave/cli/v2"
+)
+
+func TestFallbackParseCli(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		toolType consts.ToolType
+		verbose  bool
+		wantErr  bool
+	}{
+		{
+			name:     "kitex flags with spaces",
+			args:     []string{"kitex", "-module", "example.com/my app", "-thrift", "template=slim", "idl/echo.thrift"},
+			toolType: consts.KitexTool,
+		},
+		{
+			name:     "kitex repeated flags",
+			args:     []string{"kitex", "-thrift", "naming_style=golint", "-thrift", "gen_setter", "-I", "a", "-I", "b", "idl/echo.thrift"},
+			toolType: consts.KitexTool,
+		},
+		{
+			name:     "kitex verbose",
+			args:     []string{"kitex", "-v", "-module", "example.com/app", "idl/echo.thrift"},
+			toolType: consts.KitexTool,
+			verbose:  true,
+		},
+		{
+			name:     "hz verbose with value",
+			args:     []string{"hz", "new", "--verbose=true", "--idl", "idl/hello world.thrift"},
+			toolType: consts.Hz,
+			verbose:  true,
+		},
+		{
+			name:     "hz verbose alias",
+			args:     []string{"hz", "new", "-vv", "--idl", "idl/echo.thrift"},
+			toolType: consts.Hz,
+			verbose:  true,
+		},
+		{
+			name:     "hz version",
+			args:     []string{"hz", "-v"},
+			toolType: consts.Hz,
+		},
+		{
+			name:     "kitex vv is not verbose",
+			args:     []string{"kitex", "-vv", "idl/echo.thrift"},
+			toolType: consts.KitexTool,
+		},
+		{
+			name:     "hz verbose disabled",
+			args:     []string{"hz", "update", "--verbose=false", "--idl", "idl/echo.thrift"},
+			toolType: consts.Hz,
+		},
+		{
+			name:    "unknown tool",
+			args:    []string{"protoc", "--go_out=."},
+			wantErr: true,
+		},
+		{
+			name:    "no tool",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			arg := NewFallbackArgument()
+			app := &cli.App{
+				Commands: []*cli.Command{
+					{
+						Name:            "fallback",
+						SkipFlagParsing: true,
+						Action:          arg.ParseCli,
+					},
+				},
+			}
+
+			err := app.Run(append([]string{"cwgo", "fallback"}, c.args...))
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if arg.ToolType != c.toolType {
+				t.Errorf("expected tool type: %s, got: %s", c.toolType, arg.ToolType)
+			}
+			if !reflect.DeepEqual(arg.Args, c.args) {
+				t.Errorf("expected args: %q, got: %q", c.args, arg.Args)
+			}
+			if arg.Verbose != c.verbose {
+				t.Errorf("expected verbose: %v, got: %v", c.verbose, arg.Verbose)
+			}
+		})
+	}
+}
+
+func TestFallbackHelp(t *testing.T) {
+	for _, help := range []string{"-h", "--help", "-help"} {
+		t.Run(help, func(t *testing.T) {
+			arg := NewFallbackArgument()
+			out := new(bytes.Buffer)
+			app := &cli.App{
+				Writer: out,
+				Commands: []*cli.Command{
+					{
+						Name:            "fallback",
+						Usage:           "fallback to hz or kitex",
+						SkipFlagParsing: true,
+						Action:          arg.ParseCli,
+					},
+				},
+			}
+
+			if err := app.Run([]string{"cwgo", "fallback", help}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), "fallback to hz or kitex") {
+				t.Errorf("help of fallback is not printed, got: %s", out)
+			}
+			if arg.ToolType != "" {
+				t.Errorf("expected no tool to run, got: %s", arg.ToolType)
+			}
+		})
+	}
+}
@@ -26,6 +26,8 @@ import (
 type ModelArgument struct {
 	DSN               string
 	Type              string
+	Schema            string
+	WithPartitions    bool
 	Tables            []string
 	ExcludeTables     []string
 	OnlyModel         bool
@@ -49,6 +51,8 @@ func NewModelArgument() *ModelArgument {
 func (c *ModelArgument) ParseCli(ctx *cli.Context) error {
 	c.DSN = ctx.String(consts.DSN)
 	c.Type = strings.ToLower(ctx.String(consts.DBType))
+	c.Schema = ctx.String(consts.Schema)
+	c.WithPartitions = ctx.Bool(consts.Partitions)
 	c.Tables = ctx.StringSlice(consts.Tables)
 	c.ExcludeTables = ctx.StringSlice(consts.ExcludeTables)
 	c.OnlyModel = ctx.Bool(consts.OnlyModel)
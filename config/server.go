@@ -32,6 +32,16 @@ type ServerArgument struct {
 	Verbose    bool
 	Hex        bool // add http listen for kitex
 
+	Deploy         string   // deploy scaffold type, e.g. docker
+	DeployTemplate string   // deploy template path
+	DeployWith     []string // middlewares wired into the deploy scaffold
+	Force          bool     // overwrite existing deploy files
+	Replicas       int      // k8s deployment replicas
+	MaxReplicas    int      // k8s hpa max replicas
+	Image          string   // k8s container image
+	CPURequest     string   // k8s container cpu request
+	MemoryRequest  string   // k8s container memory request
+
 	Cwd    string
 	GoSrc  string
 	GoPkg  string
@@ -58,6 +68,8 @@ func (s *ServerArgument) ParseCli(ctx *cli.Context) error {
 	s.Type = strings.ToUpper(ctx.String(consts.ServiceType))
 	s.Registry = strings.ToUpper(ctx.String(consts.Registry))
 	s.Verbose = ctx.Bool(consts.Verbose)
+	s.Deploy = strings.ToLower(ctx.String(consts.Deploy))
+	s.DeployWith = ctx.StringSlice(consts.DeployWith)
 	s.SliceParam.ProtoSearchPath = ctx.StringSlice(consts.ProtoSearchPath)
 	s.SliceParam.Pass = ctx.StringSlice(consts.Pass)
 	return nil
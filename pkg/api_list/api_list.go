@@ -47,14 +47,16 @@ func Api(c *config.ApiArgument) error {
 		return err
 	}
 
-	fmt.Printf("found module name: %s\n", parser.moduleName)
+	if !c.Json {
+		fmt.Printf("found module name: %s\n", parser.moduleName)
+	}
 
 	err = parser.searchFunc(moduleName, "main", make(map[string]*Var), nil)
 	if err != nil {
 		return err
 	}
 
-	parser.PrintRouters()
+	parser.PrintRouters(c.ProjectPath, c.Json)
 
 	return nil
 }
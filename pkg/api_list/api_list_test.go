@@ -243,4 +243,98 @@ var results = map[string][]*RouterParsed{
 			RoutePath: "/api/v1/user/nickname",
 		},
 	},
+	// project with handlers, middlewares generated by hz and dynamic registrations
+	"case4": {
+		{
+			FilePath:  "main.go",
+			StartLine: 31,
+			EndLine:   31,
+			Method:    RouterRegisterFuncNameGET,
+			RoutePath: "/ping",
+			Handler:   "handler.Ping",
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 32,
+			EndLine:   32,
+			Method:    RouterRegisterFuncNamePOST,
+			RoutePath: "/hello",
+			Handler:   "handler.Hello",
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 33,
+			EndLine:   33,
+			Method:    RouterRegisterFuncNameGETEX,
+			RoutePath: "/getex",
+			Handler:   "handler.Ping",
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 34,
+			EndLine:   34,
+			Method:    RouterRegisterFuncNamePUT,
+			RoutePath: "/anonymous",
+			Handler:   AnonymousHandler,
+		},
+		{
+			FilePath:     "main.go",
+			StartLine:    37,
+			EndLine:      37,
+			Method:       RouterRegisterFuncNameGET,
+			RoutePath:    "{path}",
+			Handler:      "handler.Ping",
+			Unresolvable: true,
+		},
+		{
+			FilePath:     "main.go",
+			StartLine:    40,
+			EndLine:      40,
+			Method:       RouterRegisterFuncNameGET,
+			RoutePath:    `{os.Getenv("ROUTE_PREFIX")}/get`,
+			Handler:      "handler.Ping",
+			Unresolvable: true,
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 43,
+			EndLine:   43,
+			Method:    RouterRegisterFuncNameGET,
+			RoutePath: "/debug",
+			Handler:   "handler.Ping",
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 45,
+			EndLine:   45,
+			Method:    RouterRegisterFuncNameGET,
+			RoutePath: "/trace",
+			Handler:   "handler.Ping",
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 48,
+			EndLine:   48,
+			Method:    RouterRegisterFuncNameAny,
+			RoutePath: "/any",
+			Handler:   "handler.Ping",
+		},
+		{
+			FilePath:  "main.go",
+			StartLine: 49,
+			EndLine:   49,
+			Method:    "LOCK",
+			RoutePath: "/lock",
+			Handler:   "handler.Hello",
+		},
+		{
+			FilePath:     "main.go",
+			StartLine:    52,
+			EndLine:      52,
+			Method:       "{method}",
+			RoutePath:    "/method",
+			Handler:      "handler.Ping",
+			Unresolvable: true,
+		},
+	},
 }
@@ -33,6 +33,8 @@ const (
 	RouterRegisterFuncNameHEAD    = "HEAD"
 	RouterRegisterFuncNamePATCH   = "PATCH"
 	RouterRegisterFuncNameOPTIONS = "OPTIONS"
+	RouterRegisterFuncNameAny     = "Any"
+	RouterRegisterFuncNameHandle  = "Handle"
 
 	RouterRegisterFuncNameGETEX    = "GETEX"
 	RouterRegisterFuncNamePOSTEX   = "POSTEX"
@@ -75,6 +77,8 @@ var (
 		RouterRegisterFuncNameHEAD:     {},
 		RouterRegisterFuncNamePATCH:    {},
 		RouterRegisterFuncNameOPTIONS:  {},
+		RouterRegisterFuncNameAny:      {},
+		RouterRegisterFuncNameHandle:   {},
 		RouterRegisterFuncNameGETEX:    {},
 		RouterRegisterFuncNamePOSTEX:   {},
 		RouterRegisterFuncNamePUTEX:    {},
@@ -102,3 +106,6 @@ var (
 		BuiltinFuncNamePrintln: {},
 	}
 )
+
+// AnonymousHandler is the handler name of func literals
+const AnonymousHandler = "<anonymous>"
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"main/handler"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+)
+
+func main() {
+	h := server.Default()
+	h.GET("/ping", handler.Ping)
+	h.POST("/hello", append(_helloMw(), handler.Hello)...)
+	h.GETEX("/getex", handler.Ping, "ping")
+	h.PUT("/anonymous", func(ctx context.Context, c *app.RequestContext) {})
+
+	path := os.Getenv("ROUTE_PATH")
+	h.GET(path, handler.Ping)
+
+	g := h.Group(os.Getenv("ROUTE_PREFIX"))
+	g.GET("/get", handler.Ping)
+
+	if os.Getenv("DEBUG") != "" {
+		h.GET("/debug", handler.Ping)
+	} else if os.Getenv("TRACE") != "" {
+		h.GET("/trace", handler.Ping)
+	}
+
+	h.Any("/any", handler.Ping)
+	h.Handle("LOCK", "/lock", handler.Hello)
+
+	method := os.Getenv("ROUTE_METHOD")
+	h.Handle(method, "/method", handler.Ping)
+}
+
+func _helloMw() []app.HandlerFunc {
+	return nil
+}
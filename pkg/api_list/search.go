@@ -21,9 +21,12 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/bytedance/sonic"
 )
@@ -42,11 +45,13 @@ type Parser struct {
 }
 
 type RouterParsed struct {
-	FilePath  string `json:"file_path"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
-	Method    string `json:"method"`
-	RoutePath string `json:"route_path"`
+	FilePath     string `json:"file_path"`
+	StartLine    int    `json:"start_line"`
+	EndLine      int    `json:"end_line"`
+	Method       string `json:"method"`
+	RoutePath    string `json:"route_path"`
+	Handler      string `json:"handler"`
+	Unresolvable bool   `json:"unresolvable,omitempty"` // route path is built at runtime
 }
 
 type FuncParsed struct {
@@ -61,9 +66,10 @@ type ImportParsed struct {
 }
 
 type Var struct {
-	Name   string // variable name TODO: not consider shadowed declaration
-	Type   VarType
-	Prefix string
+	Name         string // variable name TODO: not consider shadowed declaration
+	Type         VarType
+	Prefix       string
+	Unresolvable bool // prefix contains a part which is built at runtime
 }
 
 func NewParser(projectPath, hertzRepoUrl string) (*Parser, error) {
@@ -246,35 +252,7 @@ func (p *Parser) searchStmts(stmts []ast.Stmt, packageName string, funcParsed *F
 								// var is existed in local group var map
 								if _, ok := RouterFuncNameMap[exprXCallExprFun.Sel.Name]; ok {
 									// is calling register func
-
-									// get relativePath in func param
-									if len(exprXCallExpr.Args) > 0 {
-										switch paramExpr := exprXCallExpr.Args[0].(type) {
-										// get first param(relativePath) of router func
-										case *ast.BasicLit:
-											// if param is literal
-											if paramExpr.Kind == token.STRING {
-												// if parma is string
-												relativePath := strings.Trim(paramExpr.Value, "\"")
-												fullRouter := filepath.Join(v.Prefix, relativePath)
-
-												startLine := p.fSet.Position(stmt.Pos()).Line
-												endLine := p.fSet.Position(stmt.End()).Line
-
-												p.routerParsedList = append(p.routerParsedList, &RouterParsed{
-													FilePath:  funcParsed.filePath,
-													StartLine: startLine,
-													EndLine:   endLine,
-													Method:    exprXCallExprFun.Sel.Name,
-													RoutePath: fullRouter,
-												})
-											} else {
-												continue
-											}
-										case *ast.Ident:
-											// TODO: if param is var
-										}
-									}
+									p.addRouter(stmt, funcParsed, v, exprXCallExprFun.Sel.Name, exprXCallExpr.Args)
 								}
 							}
 						}
@@ -341,16 +319,24 @@ func (p *Parser) searchStmts(stmts []ast.Stmt, packageName string, funcParsed *F
 														if v, ok := localGroupVarMap[xExpr.Name]; ok {
 															if lhsIdent, ok := lhs.(*ast.Ident); ok {
 																localGroupVarMap[lhsIdent.Name] = &Var{
-																	Name:   lhsIdent.Name,
-																	Type:   VarTypeRouterGroup,
-																	Prefix: filepath.Join(v.Prefix, strings.Trim(paramExpr.Value, "\"")),
+																	Name:         lhsIdent.Name,
+																	Type:         VarTypeRouterGroup,
+																	Prefix:       filepath.Join(v.Prefix, strings.Trim(paramExpr.Value, "\"")),
+																	Unresolvable: v.Unresolvable,
 																}
 																continue
 															}
 														}
 													}
-												} else {
-													// TODO: if param is var
+												} else if lhsIdent, ok := lhs.(*ast.Ident); ok {
+													// prefix is not a literal, routes of the group can not be resolved
+													localGroupVarMap[lhsIdent.Name] = &Var{
+														Name:         lhsIdent.Name,
+														Type:         VarTypeRouterGroup,
+														Prefix:       dynamicPath(v.Prefix, rhsExpr.Args[0]),
+														Unresolvable: true,
+													}
+													continue
 												}
 											}
 										}
@@ -386,11 +372,27 @@ func (p *Parser) searchStmts(stmts []ast.Stmt, packageName string, funcParsed *F
 			}
 
 			if stmt.Else != nil {
-				err = p.searchStmts(stmt.Else.(*ast.BlockStmt).List, packageName, funcParsed, localGroupVarMap)
+				// else may be a block or another if stmt
+				err = p.searchStmts([]ast.Stmt{stmt.Else}, packageName, funcParsed, localGroupVarMap)
 				if err != nil {
 					return err
 				}
 			}
+		case *ast.BlockStmt:
+			err := p.searchStmts(stmt.List, packageName, funcParsed, localGroupVarMap)
+			if err != nil {
+				return err
+			}
+		case *ast.ForStmt:
+			err := p.searchStmts(stmt.Body.List, packageName, funcParsed, localGroupVarMap)
+			if err != nil {
+				return err
+			}
+		case *ast.RangeStmt:
+			err := p.searchStmts(stmt.Body.List, packageName, funcParsed, localGroupVarMap)
+			if err != nil {
+				return err
+			}
 		case *ast.SwitchStmt:
 			err := p.searchStmts(stmt.Body.List, packageName, funcParsed, localGroupVarMap)
 			if err != nil {
@@ -426,14 +428,21 @@ func (p *Parser) getVarsInArgs(varMap map[string]*Var, expr *ast.CallExpr) []*Va
 										if paramExpr.Kind == token.STRING {
 											if v, ok := varMap[xIdent.Name]; ok {
 												res = append(res, &Var{
-													Type:   VarTypeRouterGroup,
-													Prefix: filepath.Join(v.Prefix, strings.Trim(paramExpr.Value, "\"")),
+													Type:         VarTypeRouterGroup,
+													Prefix:       filepath.Join(v.Prefix, strings.Trim(paramExpr.Value, "\"")),
+													Unresolvable: v.Unresolvable,
 												})
 												continue
 											}
 										}
 									} else {
-										// TODO: if param is var
+										// prefix is not a literal, routes of the group can not be resolved
+										res = append(res, &Var{
+											Type:         VarTypeRouterGroup,
+											Prefix:       dynamicPath(v.Prefix, argExpr.Args[0]),
+											Unresolvable: true,
+										})
+										continue
 									}
 								}
 							}
@@ -444,8 +453,9 @@ func (p *Parser) getVarsInArgs(varMap map[string]*Var, expr *ast.CallExpr) []*Va
 		case *ast.Ident:
 			if v, ok := varMap[argExpr.Name]; ok {
 				res = append(res, &Var{
-					Type:   v.Type,
-					Prefix: v.Prefix,
+					Type:         v.Type,
+					Prefix:       v.Prefix,
+					Unresolvable: v.Unresolvable,
 				})
 				continue
 			}
@@ -471,7 +481,110 @@ func (p *Parser) getVarsInArgs(varMap map[string]*Var, expr *ast.CallExpr) []*Va
 	return res
 }
 
-func (p *Parser) PrintRouters() {
-	j, _ := sonic.Marshal(p.routerParsedList)
-	fmt.Println(string(j))
+// addRouter records a route registered by calling router func funcName on router var v
+func (p *Parser) addRouter(stmt ast.Stmt, funcParsed *FuncParsed, v *Var, funcName string, args []ast.Expr) {
+	if len(args) == 0 {
+		return
+	}
+
+	router := &RouterParsed{
+		FilePath:     funcParsed.filePath,
+		StartLine:    p.fSet.Position(stmt.Pos()).Line,
+		EndLine:      p.fSet.Position(stmt.End()).Line,
+		Method:       funcName,
+		Unresolvable: v.Unresolvable,
+	}
+
+	if funcName == RouterRegisterFuncNameHandle {
+		// Handle(httpMethod, relativePath, handlers...)
+		if len(args) < 2 {
+			return
+		}
+		if paramExpr, ok := args[0].(*ast.BasicLit); ok && paramExpr.Kind == token.STRING {
+			router.Method = strings.Trim(paramExpr.Value, "\"")
+		} else {
+			// httpMethod is a var or an expression
+			router.Method = "{" + types.ExprString(args[0]) + "}"
+			router.Unresolvable = true
+		}
+		args = args[1:]
+	}
+	router.Handler = handlerName(funcName, args)
+
+	// get first param(relativePath) of router func
+	if paramExpr, ok := args[0].(*ast.BasicLit); ok && paramExpr.Kind == token.STRING {
+		router.RoutePath = filepath.Join(v.Prefix, strings.Trim(paramExpr.Value, "\""))
+	} else {
+		// relativePath is a var or an expression
+		router.RoutePath = dynamicPath(v.Prefix, args[0])
+		router.Unresolvable = true
+	}
+
+	p.routerParsedList = append(p.routerParsedList, router)
+}
+
+// handlerName returns the source expression of the handler registered by router func funcName,
+// args starts with relativePath. handlers wrapped with middlewares by hz,
+// e.g. append(_mw(), handler.Hello)..., are unwrapped.
+func handlerName(funcName string, args []ast.Expr) string {
+	var handler ast.Expr
+	if strings.HasSuffix(funcName, "EX") {
+		// XXEX(relativePath, handler, handlerName)
+		if len(args) < 2 {
+			return ""
+		}
+		handler = args[1]
+	} else {
+		handler = args[len(args)-1]
+	}
+
+	for {
+		callExpr, ok := handler.(*ast.CallExpr)
+		if !ok || len(callExpr.Args) == 0 {
+			break
+		}
+		if ident, ok := callExpr.Fun.(*ast.Ident); !ok || ident.Name != BuiltinFuncNameAppend {
+			break
+		}
+		handler = callExpr.Args[len(callExpr.Args)-1]
+	}
+
+	switch h := handler.(type) {
+	case *ast.Ident:
+		if h.Name == "nil" {
+			return ""
+		}
+	case *ast.FuncLit:
+		return AnonymousHandler
+	}
+	return types.ExprString(handler)
+}
+
+// dynamicPath joins prefix with the source expression of a path which is built at runtime.
+func dynamicPath(prefix string, expr ast.Expr) string {
+	return filepath.Join(prefix, "{"+types.ExprString(expr)+"}")
+}
+
+// PrintRouters prints parsed routers as json when asJSON is true, or as a table otherwise.
+func (p *Parser) PrintRouters(projectPath string, asJSON bool) {
+	if asJSON {
+		j, _ := sonic.Marshal(p.routerParsedList)
+		fmt.Println(string(j))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER\tLOCATION")
+	for _, router := range p.routerParsedList {
+		routePath := router.RoutePath
+		if router.Unresolvable {
+			routePath += " (unresolvable)"
+		}
+		filePath, err := filepath.Rel(projectPath, router.FilePath)
+		if err != nil {
+			filePath = router.FilePath
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s:%d\n", router.Method, routePath, router.Handler, filePath, router.StartLine)
+	}
+	w.Flush()
 }
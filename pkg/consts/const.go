@@ -19,8 +19,9 @@ package consts
 import "runtime"
 
 const (
-	Kitex = "kitex"
-	Hertz = "hertz"
+	Kitex  = "kitex"
+	Hertz  = "hertz"
+	Deploy = "deploy"
 )
 
 const (
@@ -96,6 +97,17 @@ const (
 	Polaris = "POLARIS"
 )
 
+// Deploy Type
+const (
+	Docker = "docker"
+	K8s    = "k8s"
+)
+
+// Middleware
+const (
+	Redis = "redis"
+)
+
 type DataBaseType string
 
 // DataBase Name
@@ -141,8 +153,11 @@ const (
 
 	ProjectPath   = "project_path"
 	HertzRepoUrl  = "hertz_repo_url"
+	Json          = "json"
 	DSN           = "dsn"
 	DBType        = "db_type"
+	Schema        = "schema"
+	Partitions    = "partitions"
 	Tables        = "tables"
 	ExcludeTables = "exclude_tables"
 	OnlyModel     = "only_model"
@@ -154,6 +169,15 @@ const (
 	IndexTag      = "index_tag"
 	TypeTag       = "type_tag"
 	HexTag        = "hex"
+
+	DeployTemplate = "deploy_template"
+	DeployWith     = "deploy_with"
+	Force          = "force"
+	Replicas       = "replicas"
+	MaxReplicas    = "max_replicas"
+	Image          = "image"
+	CPURequest     = "cpu_request"
+	MemoryRequest  = "memory_request"
 )
 
 const (
@@ -0,0 +1,388 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/cloudwego/cwgo/config"
+	"github.com/cloudwego/cwgo/pkg/common/utils"
+	"github.com/cloudwego/cwgo/pkg/consts"
+	"github.com/cloudwego/cwgo/tpl"
+	"github.com/cloudwego/hertz/cmd/hz/util/logs"
+	"github.com/cloudwego/kitex/tool/internal_pkg/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// onlineConf is the config file read by the standard templates when GO_ENV=online
+const onlineConf = "conf/online/conf.yaml"
+
+// update behavior types of deploy templates
+const (
+	coverUpdate = "cover" // always overwrite the existing file
+	mergeUpdate = "merge" // add the missing keys of the rendered yaml to the existing file
+)
+
+// default listen ports of the standard kitex and hertz templates,
+// used when conf/online/conf.yaml has no address
+const (
+	kitexPort = 8888
+	hertzPort = 8080
+)
+
+// addresses of the dependencies inside the deploy network, the hosts are
+// the service names in the docker compose file or the k8s cluster
+var registryAddresses = map[string]string{
+	consts.Etcd:    "etcd:2379",
+	consts.Nacos:   "nacos:8848",
+	consts.Zk:      "zookeeper:2181",
+	consts.Polaris: "polaris:8091",
+}
+
+const (
+	mysqlAddress = "mysql:3306"
+	redisAddress = "redis:6379"
+)
+
+// Data is the render data of deploy templates.
+type Data struct {
+	ServiceName     string
+	AppName         string // service name in DNS-1123 form, used for k8s resources
+	Module          string
+	GoVersion       string
+	Type            string
+	Port            int
+	Registry        string
+	RegistryAddress string // address of the registry inside the deploy network, e.g. etcd:2379
+	WithMySQL       bool
+	WithRedis       bool
+	Dependencies    []string
+
+	Replicas      int
+	MaxReplicas   int
+	Image         string
+	CPURequest    string
+	MemoryRequest string
+	Conf          string // conf/online/conf.yaml with the addresses of the dependencies
+}
+
+// Deploy generates the deploy scaffold of the server into its output directory.
+func Deploy(c *config.ServerArgument) error {
+	dir, err := templateDir(c)
+	if err != nil {
+		return err
+	}
+
+	templates, err := loadTemplates(dir)
+	if err != nil {
+		return err
+	}
+
+	data := newData(c)
+	for _, t := range templates {
+		if err = render(c, t, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func templateDir(c *config.ServerArgument) (string, error) {
+	// Non-standard template
+	if strings.HasSuffix(c.DeployTemplate, consts.SuffixGit) {
+		err := utils.GitClone(c.DeployTemplate, tpl.DeployDir)
+		if err != nil {
+			return "", err
+		}
+		gitPath, err := utils.GitPath(c.DeployTemplate)
+		if err != nil {
+			return "", err
+		}
+		return path.Join(tpl.DeployDir, gitPath), nil
+	}
+	if len(c.DeployTemplate) != 0 {
+		return c.DeployTemplate, nil
+	}
+	return path.Join(tpl.DeployDir, c.Deploy), nil
+}
+
+func loadTemplates(dir string) ([]*generator.Template, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read deploy template dir %s failed: %w", dir, err)
+	}
+
+	var templates []*generator.Template
+	for _, f := range files {
+		// filter dir and non-yaml files
+		if f.IsDir() || !(strings.HasSuffix(f.Name(), "yaml") || strings.HasSuffix(f.Name(), "yml")) {
+			continue
+		}
+		content, err := utils.ReadFileContent(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		t := new(generator.Template)
+		if err = yaml.Unmarshal(content, t); err != nil {
+			return nil, fmt.Errorf("parse deploy template %s failed: %w", f.Name(), err)
+		}
+		if t.Path == "" {
+			return nil, fmt.Errorf("deploy template %s has no path", f.Name())
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+func render(c *config.ServerArgument, t *generator.Template, data *Data) error {
+	filePath := filepath.Join(c.OutDir, t.Path)
+
+	isExist, err := utils.PathExist(filePath)
+	if err != nil {
+		return err
+	}
+	var updateType string
+	if t.UpdateBehavior != nil {
+		updateType = t.UpdateBehavior.Type
+	}
+	if isExist && !c.Force && updateType != coverUpdate && updateType != mergeUpdate {
+		logs.Warnf("%s already exists, skip it. (use --force to overwrite)", t.Path)
+		return nil
+	}
+
+	tmpl, err := template.New(t.Path).Funcs(sprig.TxtFuncMap()).Parse(t.Body)
+	if err != nil {
+		return fmt.Errorf("parse deploy template %s failed: %w", t.Path, err)
+	}
+	buf := new(bytes.Buffer)
+	if err = tmpl.Execute(buf, data); err != nil {
+		return fmt.Errorf("render deploy template %s failed: %w", t.Path, err)
+	}
+	// templates rendered to nothing are not needed, e.g. the conf without conf/online/conf.yaml
+	if strings.TrimSpace(buf.String()) == "" {
+		return nil
+	}
+
+	content := buf.Bytes()
+	if isExist && !c.Force && updateType == mergeUpdate {
+		existing, err := utils.ReadFileContent(filePath)
+		if err != nil {
+			return err
+		}
+		if content, err = mergeYAML(existing, content); err != nil {
+			return fmt.Errorf("merge deploy template %s into the existing file failed: %w", t.Path, err)
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+	return utils.CreateFile(filePath, string(content))
+}
+
+// mergeYAML adds the keys of src missing in dst recursively, the values in dst are kept.
+func mergeYAML(dst, src []byte) ([]byte, error) {
+	var dstDoc, srcDoc yaml.Node
+	if err := yaml.Unmarshal(dst, &dstDoc); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(src, &srcDoc); err != nil {
+		return nil, err
+	}
+	if len(dstDoc.Content) == 0 {
+		return src, nil
+	}
+	if len(srcDoc.Content) != 0 {
+		mergeNode(dstDoc.Content[0], srcDoc.Content[0])
+	}
+	return encodeYAML(&dstDoc)
+}
+
+func mergeNode(dst, src *yaml.Node) {
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if value := mappingValue(dst, src.Content[i].Value); value != nil {
+			mergeNode(value, src.Content[i+1])
+			continue
+		}
+		dst.Content = append(dst.Content, src.Content[i], src.Content[i+1])
+	}
+}
+
+// mappingValue returns the value of the key path in a yaml mapping node, or nil if not found.
+func mappingValue(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var value *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				value = node.Content[i+1]
+				break
+			}
+		}
+		node = value
+	}
+	return node
+}
+
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newData(c *config.ServerArgument) *Data {
+	data := &Data{
+		ServiceName:   c.Service,
+		AppName:       AppName(c.Service),
+		Module:        c.GoMod,
+		GoVersion:     goVersion(c.OutDir),
+		Type:          c.Type,
+		Port:          kitexPort,
+		Registry:      c.Registry,
+		Replicas:      c.Replicas,
+		MaxReplicas:   c.MaxReplicas,
+		Image:         c.Image,
+		CPURequest:    c.CPURequest,
+		MemoryRequest: c.MemoryRequest,
+	}
+	if c.Type == consts.HTTP {
+		data.Port = hertzPort
+	}
+	if data.Image == "" {
+		data.Image = data.AppName + ":latest"
+	}
+
+	if addr, ok := registryAddresses[c.Registry]; ok {
+		host, _, _ := net.SplitHostPort(addr)
+		data.RegistryAddress = addr
+		data.Dependencies = append(data.Dependencies, host)
+	}
+	for _, m := range c.DeployWith {
+		switch m {
+		case string(consts.MySQL):
+			data.WithMySQL = true
+		case consts.Redis:
+			data.WithRedis = true
+		}
+		data.Dependencies = append(data.Dependencies, m)
+	}
+
+	if conf, err := utils.ReadFileContent(filepath.Join(c.OutDir, onlineConf)); err == nil {
+		data.Conf = strings.TrimSpace(string(conf))
+		if err = data.parseConf(c, conf); err != nil {
+			logs.Warnf("parse %s failed, it is deployed as is: %v", onlineConf, err)
+		}
+	}
+	return data
+}
+
+var mysqlHostReg = regexp.MustCompile(`tcp\([^)]*\)`)
+
+// parseConf reads the listen port from the online conf of the server,
+// and points the addresses of the registry and middlewares to the deploy services.
+func (d *Data) parseConf(c *config.ServerArgument, conf []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(conf, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+
+	server := "kitex"
+	if c.Type == consts.HTTP {
+		server = "hertz"
+	}
+	if addr := mappingValue(root, server, "address"); addr != nil {
+		_, port, err := net.SplitHostPort(addr.Value)
+		if err != nil {
+			return fmt.Errorf("invalid %s.address %q: %w", server, addr.Value, err)
+		}
+		if d.Port, err = strconv.Atoi(port); err != nil {
+			return fmt.Errorf("invalid %s.address %q: %w", server, addr.Value, err)
+		}
+	}
+
+	if addr, ok := registryAddresses[c.Registry]; ok {
+		if node := mappingValue(root, "registry", "registry_address"); node != nil && node.Kind == yaml.SequenceNode {
+			node.Content = []*yaml.Node{{Kind: yaml.ScalarNode, Value: addr}}
+		}
+	}
+	if node := mappingValue(root, "mysql", "dsn"); node != nil && d.WithMySQL {
+		node.Value = mysqlHostReg.ReplaceAllLiteralString(node.Value, "tcp("+mysqlAddress+")")
+	}
+	if node := mappingValue(root, "redis", "address"); node != nil && d.WithRedis {
+		node.Value = redisAddress
+	}
+
+	content, err := encodeYAML(&doc)
+	if err != nil {
+		return err
+	}
+	d.Conf = strings.TrimSpace(string(content))
+	return nil
+}
+
+var invalidNameReg = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// AppName converts the service name to a valid DNS-1123 label, e.g. echo_svc -> echo-svc.
+// It returns "" if the service name has no ASCII letter or digit.
+func AppName(service string) string {
+	return strings.Trim(invalidNameReg.ReplaceAllString(strings.ToLower(service), "-"), "-")
+}
+
+var goVersionReg = regexp.MustCompile(`(?m)^\s*go\s+(\d+\.\d+)`)
+
+// goVersion returns the go version declared in go.mod of dir,
+// and falls back to the version of the running toolchain.
+func goVersion(dir string) string {
+	content, err := utils.ReadFileContent(filepath.Join(dir, consts.GoMod))
+	if err == nil {
+		if m := goVersionReg.FindSubmatch(content); m != nil {
+			return string(m[1])
+		}
+	}
+	v := strings.TrimPrefix(runtime.Version(), consts.Go)
+	if parts := strings.SplitN(v, ".", 3); len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return v
+}
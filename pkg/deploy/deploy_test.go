@@ -0,0 +1,508 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/cwgo/config"
+	"github.com/cloudwego/cwgo/pkg/consts"
+	"gopkg.in/yaml.v3"
+)
+
+// embedded templates are read from the source tree, so that tpl.Init
+// does not touch the shared template dirs under os.TempDir.
+func srcTemplateDir(kind string) string {
+	return filepath.Join("..", "..", "tpl", consts.Deploy, kind)
+}
+
+func newArgs(t *testing.T, kind string) *config.ServerArgument {
+	c := config.NewServerArgument()
+	c.Service = "echo_svc"
+	c.Type = consts.HTTP
+	c.GoMod = "example.com/echo"
+	c.OutDir = t.TempDir()
+	c.Registry = consts.Etcd
+	c.Deploy = kind
+	c.DeployTemplate = srcTemplateDir(kind)
+	c.DeployWith = []string{string(consts.MySQL), consts.Redis}
+	c.Replicas = 1
+	c.MaxReplicas = 3
+	c.CPURequest = "100m"
+	c.MemoryRequest = "128Mi"
+	return c
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeploy(t *testing.T) {
+	tests := map[string][]string{
+		consts.Docker: {"Dockerfile", ".dockerignore", "docker-compose.yaml"},
+		consts.K8s: {
+			"deploy/k8s/deployment.yaml",
+			"deploy/k8s/service.yaml",
+			"deploy/k8s/hpa.yaml",
+			"deploy/k8s/configmap.yaml",
+			"deploy/k8s/kustomization.yaml",
+		},
+	}
+	for kind, files := range tests {
+		for _, typ := range []string{consts.HTTP, consts.RPC} {
+			t.Run(kind+"/"+typ, func(t *testing.T) {
+				c := newArgs(t, kind)
+				c.Type = typ
+				if err := Deploy(c); err != nil {
+					t.Fatal(err)
+				}
+
+				for _, f := range files {
+					content := readFile(t, filepath.Join(c.OutDir, f))
+					if strings.TrimSpace(content) == "" {
+						t.Errorf("%s is empty", f)
+					}
+					if !strings.HasSuffix(f, ".yaml") {
+						continue
+					}
+					// every document in the file must be valid yaml
+					dec := yaml.NewDecoder(bytes.NewBufferString(content))
+					for {
+						var doc interface{}
+						if err := dec.Decode(&doc); err != nil {
+							if !errors.Is(err, io.EOF) {
+								t.Errorf("%s is not valid yaml: %v\n%s", f, err, content)
+							}
+							break
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// onlineConfs are conf/online/conf.yaml of the standard templates with a non-default port
+var onlineConfs = map[string]string{
+	consts.RPC: `kitex:
+  service: "echo_svc"
+  address: ":9999"
+  log_level: info
+
+registry:
+  registry_address:
+    - 127.0.0.1:2379
+  username: ""
+  password: ""
+
+mysql:
+  dsn: "gorm:gorm@tcp(127.0.0.1:3306)/gorm?charset=utf8mb4&parseTime=True&loc=Local"
+
+redis:
+  address: "127.0.0.1:6379"
+  db: 0
+`,
+	consts.HTTP: `hertz:
+  address: "0.0.0.0:9999"
+  enable_pprof: false
+
+mysql:
+  dsn: "gorm:gorm@tcp(127.0.0.1:3306)/gorm?charset=utf8mb4&parseTime=True&loc=Local"
+
+redis:
+  address: "127.0.0.1:6379"
+  db: 0
+`,
+}
+
+// standardCompose is docker-compose.yaml generated by the standard templates
+const standardCompose = `version: '3'
+services:
+  mysql:
+    image: 'mysql:latest'
+    ports:
+      - 3306:3306
+  redis:
+    image: 'redis:latest'
+    ports:
+      - 6379:6379
+`
+
+type composeFile struct {
+	Services map[string]struct {
+		Image     string   `yaml:"image"`
+		Ports     []string `yaml:"ports"`
+		Volumes   []string `yaml:"volumes"`
+		DependsOn []string `yaml:"depends_on"`
+	} `yaml:"services"`
+}
+
+type serverConf struct {
+	Kitex    struct{ Address string } `yaml:"kitex"`
+	Hertz    struct{ Address string } `yaml:"hertz"`
+	Registry struct {
+		RegistryAddress []string `yaml:"registry_address"`
+	} `yaml:"registry"`
+	MySQL struct{ DSN string }     `yaml:"mysql"`
+	Redis struct{ Address string } `yaml:"redis"`
+}
+
+func unmarshalFile(t *testing.T, path string, out interface{}) {
+	content := readFile(t, path)
+	if err := yaml.Unmarshal([]byte(content), out); err != nil {
+		t.Fatalf("%s is not valid yaml: %v\n%s", path, err, content)
+	}
+}
+
+func TestDeployDocker(t *testing.T) {
+	for _, typ := range []string{consts.HTTP, consts.RPC} {
+		t.Run(typ, func(t *testing.T) {
+			c := newArgs(t, consts.Docker)
+			c.Type = typ
+			writeFile(t, filepath.Join(c.OutDir, onlineConf), onlineConfs[typ])
+			writeFile(t, filepath.Join(c.OutDir, "docker-compose.yaml"), standardCompose)
+			if err := Deploy(c); err != nil {
+				t.Fatal(err)
+			}
+
+			// the port is read from the conf
+			if dockerfile := readFile(t, filepath.Join(c.OutDir, "Dockerfile")); !strings.Contains(dockerfile, "EXPOSE 9999\n") {
+				t.Errorf("Dockerfile does not expose the port in the conf:\n%s", dockerfile)
+			}
+
+			// the server is added to the existing compose file, its middlewares are kept as they are
+			var compose composeFile
+			unmarshalFile(t, filepath.Join(c.OutDir, "docker-compose.yaml"), &compose)
+			if len(compose.Services) != 4 {
+				t.Errorf("got services %v, want echo-svc, etcd, mysql and redis", compose.Services)
+			}
+			app := compose.Services["echo-svc"]
+			if strings.Join(app.Ports, ",") != "9999:9999" {
+				t.Errorf("got server ports %v, want 9999:9999", app.Ports)
+			}
+			if strings.Join(app.DependsOn, ",") != "etcd,mysql,redis" {
+				t.Errorf("got server depends_on %v, want etcd, mysql and redis", app.DependsOn)
+			}
+			if strings.Join(app.Volumes, ",") != "./deploy/docker/conf.yaml:/app/conf/online/conf.yaml:ro" {
+				t.Errorf("got server volumes %v, want the docker conf", app.Volumes)
+			}
+			if ports := compose.Services["etcd"].Ports; strings.Join(ports, ",") != "2379:2379" {
+				t.Errorf("got etcd ports %v, want 2379:2379", ports)
+			}
+			if mysql := compose.Services["mysql"]; mysql.Image != "mysql:latest" || strings.Join(mysql.Ports, ",") != "3306:3306" {
+				t.Errorf("existing mysql service is changed: %+v", mysql)
+			}
+
+			// the mounted conf points to the compose services
+			var conf serverConf
+			unmarshalFile(t, filepath.Join(c.OutDir, "deploy", "docker", "conf.yaml"), &conf)
+			if conf.Kitex.Address+conf.Hertz.Address == "" {
+				t.Errorf("server address is lost in the docker conf: %+v", conf)
+			}
+			if typ == consts.RPC && strings.Join(conf.Registry.RegistryAddress, ",") != "etcd:2379" {
+				t.Errorf("got registry address %v, want etcd:2379", conf.Registry.RegistryAddress)
+			}
+			if !strings.Contains(conf.MySQL.DSN, "@tcp(mysql:3306)/gorm?") {
+				t.Errorf("got mysql dsn %s, want the host mysql:3306", conf.MySQL.DSN)
+			}
+			if conf.Redis.Address != "redis:6379" {
+				t.Errorf("got redis address %s, want redis:6379", conf.Redis.Address)
+			}
+		})
+	}
+}
+
+func TestDeployDockerWithoutConf(t *testing.T) {
+	for typ, port := range map[string]string{consts.HTTP: "8080", consts.RPC: "8888"} {
+		t.Run(typ, func(t *testing.T) {
+			c := newArgs(t, consts.Docker)
+			c.Type = typ
+			c.Registry = ""
+			c.DeployWith = nil
+			if err := Deploy(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if dockerfile := readFile(t, filepath.Join(c.OutDir, "Dockerfile")); !strings.Contains(dockerfile, "EXPOSE "+port+"\n") {
+				t.Errorf("Dockerfile does not expose the default port %s:\n%s", port, dockerfile)
+			}
+			var compose composeFile
+			unmarshalFile(t, filepath.Join(c.OutDir, "docker-compose.yaml"), &compose)
+			app, ok := compose.Services["echo-svc"]
+			if len(compose.Services) != 1 || !ok {
+				t.Fatalf("got services %v, want echo-svc only", compose.Services)
+			}
+			if strings.Join(app.Ports, ",") != port+":"+port || len(app.Volumes) != 0 || len(app.DependsOn) != 0 {
+				t.Errorf("got server service %+v", app)
+			}
+			if _, err := os.Stat(filepath.Join(c.OutDir, "deploy", "docker", "conf.yaml")); !os.IsNotExist(err) {
+				t.Errorf("docker conf is generated without %s, err: %v", onlineConf, err)
+			}
+		})
+	}
+}
+
+type k8sProbe struct {
+	HTTPGet *struct {
+		Path string `yaml:"path"`
+		Port int    `yaml:"port"`
+	} `yaml:"httpGet"`
+	TCPSocket *struct {
+		Port int `yaml:"port"`
+	} `yaml:"tcpSocket"`
+}
+
+type k8sContainer struct {
+	Name    string   `yaml:"name"`
+	Image   string   `yaml:"image"`
+	Command []string `yaml:"command"`
+	Ports   []struct {
+		Name          string `yaml:"name"`
+		ContainerPort int    `yaml:"containerPort"`
+	} `yaml:"ports"`
+	Env []struct {
+		Name string `yaml:"name"`
+	} `yaml:"env"`
+	LivenessProbe  k8sProbe `yaml:"livenessProbe"`
+	ReadinessProbe k8sProbe `yaml:"readinessProbe"`
+}
+
+// k8sResource holds the fields of the generated manifests checked by tests
+type k8sResource struct {
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				InitContainers []k8sContainer `yaml:"initContainers"`
+				Containers     []k8sContainer `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+		Ports []struct {
+			Name       string `yaml:"name"`
+			Port       int    `yaml:"port"`
+			TargetPort int    `yaml:"targetPort"`
+		} `yaml:"ports"`
+	} `yaml:"spec"`
+	Data map[string]string `yaml:"data"`
+}
+
+func TestDeployK8s(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		conf     bool
+		port     int
+		portName string
+	}{
+		{name: "HTTP", typ: consts.HTTP, port: hertzPort, portName: "http"},
+		{name: "RPC", typ: consts.RPC, port: kitexPort, portName: "rpc"},
+		{name: "HTTP with conf", typ: consts.HTTP, conf: true, port: 9999, portName: "http"},
+		{name: "RPC with conf", typ: consts.RPC, conf: true, port: 9999, portName: "rpc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newArgs(t, consts.K8s)
+			c.Type = tt.typ
+			if tt.conf {
+				writeFile(t, filepath.Join(c.OutDir, onlineConf), onlineConfs[tt.typ])
+			}
+			if err := Deploy(c); err != nil {
+				t.Fatal(err)
+			}
+
+			var deployment k8sResource
+			unmarshalFile(t, filepath.Join(c.OutDir, "deploy", "k8s", "deployment.yaml"), &deployment)
+			if deployment.Metadata.Name != "echo-svc" {
+				t.Errorf("got deployment name %s, want echo-svc", deployment.Metadata.Name)
+			}
+			containers := deployment.Spec.Template.Spec.Containers
+			if len(containers) != 1 {
+				t.Fatalf("got %d containers, want 1", len(containers))
+			}
+			container := containers[0]
+			if container.Name != "echo-svc" || container.Image != "echo-svc:latest" {
+				t.Errorf("got container %s with image %s, want echo-svc with echo-svc:latest", container.Name, container.Image)
+			}
+			if len(container.Ports) != 1 || container.Ports[0].Name != tt.portName || container.Ports[0].ContainerPort != tt.port {
+				t.Errorf("got container ports %+v, want %s %d", container.Ports, tt.portName, tt.port)
+			}
+			for _, env := range container.Env {
+				if env.Name != "GO_ENV" {
+					t.Errorf("unexpected env %s", env.Name)
+				}
+			}
+			for _, probe := range []k8sProbe{container.LivenessProbe, container.ReadinessProbe} {
+				if tt.typ == consts.HTTP {
+					if probe.HTTPGet == nil || probe.HTTPGet.Path != "/ping" || probe.HTTPGet.Port != tt.port || probe.TCPSocket != nil {
+						t.Errorf("got probe %+v, want httpGet /ping on port %d", probe, tt.port)
+					}
+				} else if probe.TCPSocket == nil || probe.TCPSocket.Port != tt.port || probe.HTTPGet != nil {
+					t.Errorf("got probe %+v, want tcpSocket on port %d", probe, tt.port)
+				}
+			}
+			// the pod waits for the registry
+			initContainers := deployment.Spec.Template.Spec.InitContainers
+			if len(initContainers) != 1 || !strings.Contains(strings.Join(initContainers[0].Command, " "), "nc -z etcd 2379") {
+				t.Errorf("got init containers %+v, want waiting for etcd:2379", initContainers)
+			}
+
+			var service k8sResource
+			unmarshalFile(t, filepath.Join(c.OutDir, "deploy", "k8s", "service.yaml"), &service)
+			if service.Metadata.Name != "echo-svc" {
+				t.Errorf("got service name %s, want echo-svc", service.Metadata.Name)
+			}
+			if ports := service.Spec.Ports; len(ports) != 1 || ports[0].Name != tt.portName || ports[0].Port != tt.port || ports[0].TargetPort != tt.port {
+				t.Errorf("got service ports %+v, want %s %d", ports, tt.portName, tt.port)
+			}
+
+			var configMap k8sResource
+			unmarshalFile(t, filepath.Join(c.OutDir, "deploy", "k8s", "configmap.yaml"), &configMap)
+			if configMap.Metadata.Name != "echo-svc-conf" {
+				t.Errorf("got configmap name %s, want echo-svc-conf", configMap.Metadata.Name)
+			}
+			if !tt.conf {
+				return
+			}
+			// the conf points to the services in the cluster
+			var conf serverConf
+			if err := yaml.Unmarshal([]byte(configMap.Data["conf.yaml"]), &conf); err != nil {
+				t.Fatal(err)
+			}
+			if tt.typ == consts.RPC && strings.Join(conf.Registry.RegistryAddress, ",") != "etcd:2379" {
+				t.Errorf("got registry address %v, want etcd:2379", conf.Registry.RegistryAddress)
+			}
+			if !strings.Contains(conf.MySQL.DSN, "@tcp(mysql:3306)/") || conf.Redis.Address != "redis:6379" {
+				t.Errorf("got mysql dsn %s and redis address %s, want the services in the cluster", conf.MySQL.DSN, conf.Redis.Address)
+			}
+		})
+	}
+}
+
+func TestDeployK8sWithoutRegistry(t *testing.T) {
+	c := newArgs(t, consts.K8s)
+	c.Registry = ""
+	c.Image = "registry.example.com/echo:v1"
+	if err := Deploy(c); err != nil {
+		t.Fatal(err)
+	}
+
+	var deployment k8sResource
+	unmarshalFile(t, filepath.Join(c.OutDir, "deploy", "k8s", "deployment.yaml"), &deployment)
+	if n := len(deployment.Spec.Template.Spec.InitContainers); n != 0 {
+		t.Errorf("got %d init containers without registry", n)
+	}
+	if containers := deployment.Spec.Template.Spec.Containers; len(containers) != 1 || containers[0].Image != c.Image {
+		t.Errorf("got containers %+v, want image %s", containers, c.Image)
+	}
+}
+
+func TestDeployExistingFiles(t *testing.T) {
+	c := newArgs(t, consts.Docker)
+	dockerfile := filepath.Join(c.OutDir, "Dockerfile")
+	writeFile(t, dockerfile, "modified")
+
+	// existing files are kept
+	if err := Deploy(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, dockerfile); got != "modified" {
+		t.Errorf("Dockerfile is overwritten without --force: %s", got)
+	}
+
+	// --force overwrites them
+	c.Force = true
+	if err := Deploy(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, dockerfile); got == "modified" {
+		t.Errorf("Dockerfile is not overwritten with --force")
+	}
+}
+
+func TestDeployCoverTemplate(t *testing.T) {
+	c := newArgs(t, consts.Docker)
+	c.DeployTemplate = t.TempDir()
+	writeFile(t, filepath.Join(c.DeployTemplate, "cover_tpl.yaml"), "path: deploy/app.txt\nupdate_behavior:\n  type: cover\nbody: \"{{.AppName}}\"\n")
+	writeFile(t, filepath.Join(c.DeployTemplate, "skip_tpl.yaml"), "path: deploy/skip.txt\nbody: \"{{.AppName}}\"\n")
+
+	cover := filepath.Join(c.OutDir, "deploy", "app.txt")
+	skip := filepath.Join(c.OutDir, "deploy", "skip.txt")
+	writeFile(t, cover, "modified")
+	writeFile(t, skip, "modified")
+
+	if err := Deploy(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, cover); got != "echo-svc" {
+		t.Errorf("cover template is not overwritten, got: %s", got)
+	}
+	if got := readFile(t, skip); got != "modified" {
+		t.Errorf("template without update behavior is overwritten, got: %s", got)
+	}
+}
+
+func TestGoVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, consts.GoMod), "module example.com/echo\n\ngo 1.21.5\n")
+	if got := goVersion(dir); got != "1.21" {
+		t.Errorf("goVersion from go.mod = %s, want 1.21", got)
+	}
+
+	// falls back to the running toolchain without go.mod
+	if got := goVersion(t.TempDir()); got == "" || !strings.HasPrefix(strings.TrimPrefix(runtime.Version(), consts.Go), got) {
+		t.Errorf("goVersion without go.mod = %s, runtime version: %s", got, runtime.Version())
+	}
+}
+
+func TestAppName(t *testing.T) {
+	tests := map[string]string{
+		"echo":     "echo",
+		"echo_svc": "echo-svc",
+		"Echo.Svc": "echo-svc",
+		"_echo_":   "echo",
+		"___":      "",
+		"服务":       "",
+		"echo服务v2": "echo-v2",
+	}
+	for service, want := range tests {
+		if got := AppName(service); got != want {
+			t.Errorf("AppName(%q) = %q, want %q", service, got, want)
+		}
+	}
+}
@@ -18,7 +18,9 @@ package fallback
 
 import (
 	"bytes"
+	"errors"
 	"os"
+	"os/exec"
 	"strings"
 
 	"github.com/cloudwego/cwgo/config"
@@ -28,6 +30,7 @@ import (
 	"github.com/cloudwego/kitex"
 	kargs "github.com/cloudwego/kitex/tool/cmd/kitex/args"
 	"github.com/cloudwego/kitex/tool/internal_pkg/pluginmode/thriftgo"
+	"github.com/urfave/cli/v2"
 )
 
 func Fallback(c *config.FallbackArgument) error {
@@ -37,9 +40,19 @@ func Fallback(c *config.FallbackArgument) error {
 		var args kargs.Arguments
 		args.ParseArgs(kitex.Version)
 
+		// resolve the compiler once, so that kitex does not look it up again
+		compilerPath, err := checkCompiler(args.IDLType, args.CompilerPath)
+		if err != nil {
+			return cli.Exit(err, 1)
+		}
+		args.CompilerPath = compilerPath
+
 		out := new(bytes.Buffer)
 		cmd := args.BuildCmd(out)
-		err := cmd.Run()
+		if c.Verbose {
+			logs.Infof("run: %s\n", formatCommand(cmd.Args))
+		}
+		err = cmd.Run()
 		if err != nil {
 			if args.Use != "" {
 				out := strings.TrimSpace(out.String())
@@ -47,7 +60,12 @@ func Fallback(c *config.FallbackArgument) error {
 					os.Exit(0)
 				}
 			}
-			os.Exit(1)
+			// keep the exit code of the underlying tool
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			return cli.Exit(err, 1)
 		}
 	case consts.Hz:
 		os.Args = c.Args
@@ -55,11 +73,28 @@ func Fallback(c *config.FallbackArgument) error {
 			logs.Flush()
 		}()
 
-		cli := app.Init()
-		err := cli.Run(os.Args)
+		if c.Verbose {
+			logs.Infof("run: %s\n", formatCommand(c.Args))
+		}
+		// errors with an exit code are handled by hz itself
+		hzApp := app.Init()
+		err := hzApp.Run(os.Args)
 		if err != nil {
-			logs.Errorf("%v\n", err)
+			return cli.Exit(err, 1)
 		}
 	}
 	return nil
 }
+
+// formatCommand joins args into a command line which can be copied to the shell.
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`;&|<>()*?") {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, consts.BlackSpace)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fallback
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestFormatCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "plain",
+			args: []string{"thriftgo", "-g", "go:naming_style=golint", "idl/echo.thrift"},
+			want: "thriftgo -g go:naming_style=golint idl/echo.thrift",
+		},
+		{
+			name: "spaces",
+			args: []string{"kitex", "-module", "example.com/my app", "idl/hello world.thrift"},
+			want: "kitex -module 'example.com/my app' 'idl/hello world.thrift'",
+		},
+		{
+			name: "single quote",
+			args: []string{"hz", "new", "--mod", "it's"},
+			want: `hz new --mod 'it'\''s'`,
+		},
+		{
+			name: "double quote",
+			args: []string{"kitex", "-thrift", `template="slim"`},
+			want: `kitex -thrift 'template="slim"'`,
+		},
+		{
+			name: "empty and shell characters",
+			args: []string{"kitex", "", "$HOME", "a;b", "*.thrift"},
+			want: "kitex '' '$HOME' 'a;b' '*.thrift'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCommand(tt.args); got != tt.want {
+				t.Errorf("formatCommand(%q) = %s, want %s", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatCommandShell checks the formatted command is parsed back to the same args by sh.
+func TestFormatCommandShell(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not found")
+	}
+	args := []string{"printf", `%s\n`, "example.com/my app", "it's", `"slim"`, "$HOME", "a;b"}
+	out, err := exec.Command(sh, "-c", formatCommand(args)).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want string
+	for _, arg := range args[2:] {
+		want += arg + "\n"
+	}
+	if string(out) != want {
+		t.Errorf("sh parsed %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fallback
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cloudwego/cwgo/pkg/consts"
+)
+
+const thriftgoModule = "github.com/cloudwego/thriftgo"
+
+// checkCompiler returns the path of the compiler used by kitex, and offers to
+// install the pinned thriftgo when it is missing.
+func checkCompiler(idlType, compilerPath string) (string, error) {
+	tool := consts.ThriftGo
+	if idlType == consts.Protobuf {
+		tool = consts.Protoc
+	}
+	if path, ok := lookupCompiler(tool, compilerPath); ok {
+		return path, nil
+	}
+
+	where := "$PATH, $GOBIN or $GOPATH/bin"
+	if compilerPath != "" {
+		where = compilerPath
+	}
+	if tool == consts.Protoc {
+		return "", fmt.Errorf("protoc is not found in %s, please install it first, refer to https://github.com/protocolbuffers/protobuf", where)
+	}
+
+	pkg := thriftgoModule + "@" + thriftgoVersion()
+	if compilerPath != "" || !isTerminal(os.Stdin) || !confirm(fmt.Sprintf("thriftgo is not found in %s, install %s now? [y/N] ", where, pkg)) {
+		return "", fmt.Errorf("thriftgo is not found in %s, please install it first:\n  go install %s", where, pkg)
+	}
+
+	cmd := exec.Command(consts.Go, "install", pkg)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("install %s failed: %w", pkg, err)
+	}
+	if path, ok := lookupCompiler(tool, ""); ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("thriftgo is installed by go install but not found in %s", where)
+}
+
+// lookupCompiler returns the path of tool, which is compilerPath if specified,
+// or found in $PATH and the dirs go install puts binaries into.
+func lookupCompiler(tool, compilerPath string) (string, bool) {
+	if compilerPath != "" {
+		_, err := os.Stat(compilerPath)
+		return compilerPath, err == nil
+	}
+	if path, err := exec.LookPath(tool); err == nil {
+		return path, true
+	}
+	if runtime.GOOS == "windows" {
+		tool += ".exe"
+	}
+	for _, dir := range goBinDirs() {
+		path := filepath.Join(dir, tool)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// goBinDirs returns the dirs go install puts binaries into, $GOBIN first and then $GOPATH/bin.
+func goBinDirs() []string {
+	out, err := exec.Command(consts.Go, "env", "GOBIN", "GOPATH").Output()
+	if err != nil {
+		return nil
+	}
+	// one value per line, GOBIN is an empty line when not set
+	lines := strings.Split(string(out), "\n")
+	var dirs []string
+	if gobin := strings.TrimSpace(lines[0]); gobin != "" {
+		dirs = append(dirs, gobin)
+	}
+	if len(lines) > 1 {
+		for _, gopath := range filepath.SplitList(strings.TrimSpace(lines[1])) {
+			dirs = append(dirs, filepath.Join(gopath, "bin"))
+		}
+	}
+	return dirs
+}
+
+// thriftgoVersion returns the thriftgo version cwgo is built with.
+func thriftgoVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == thriftgoModule {
+				return dep.Version
+			}
+		}
+	}
+	return "latest"
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+func confirm(prompt string) bool {
+	fmt.Fprint(os.Stderr, prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fallback
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cloudwego/cwgo/pkg/consts"
+)
+
+func TestLookupCompiler(t *testing.T) {
+	gobin := t.TempDir()
+	name := consts.ThriftGo
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	installed := filepath.Join(gobin, name)
+	if err := os.WriteFile(installed, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// thriftgo is only in $GOBIN, e.g. just installed by go install
+	t.Setenv("PATH", filepath.Dir(goBinary(t)))
+	t.Setenv("GOBIN", gobin)
+	t.Setenv("GOPATH", t.TempDir())
+
+	if path, ok := lookupCompiler(consts.ThriftGo, ""); !ok || path != installed {
+		t.Errorf("lookupCompiler = %s, %v, want %s", path, ok, installed)
+	}
+	if path, ok := lookupCompiler(consts.Protoc, ""); ok {
+		t.Errorf("lookupCompiler found protoc at %s", path)
+	}
+
+	// the specified path is used as is
+	if path, ok := lookupCompiler(consts.ThriftGo, installed); !ok || path != installed {
+		t.Errorf("lookupCompiler with compiler path = %s, %v, want %s", path, ok, installed)
+	}
+	missing := filepath.Join(gobin, "missing")
+	if path, ok := lookupCompiler(consts.ThriftGo, missing); ok || path != missing {
+		t.Errorf("lookupCompiler with missing compiler path = %s, %v", path, ok)
+	}
+}
+
+func goBinary(t *testing.T) string {
+	path, err := exec.LookPath(consts.Go)
+	if err != nil {
+		t.Skip("go is not found")
+	}
+	return path
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "github.com/cloudwego/cwgo/pkg/consts"
+
+type dataTypeMap = map[string]func(columnType string) (dataType string)
+
+func goType(dataType string) func(string) string {
+	return func(string) string { return dataType }
+}
+
+// baseDataTypeMap overrides the default mappings of gorm gen which lose data,
+// it is shared by all database types.
+var baseDataTypeMap = dataTypeMap{
+	"numeric": goType("float64"),
+	"decimal": goType("float64"),
+	"bool":    goType("bool"),
+	"boolean": goType("bool"),
+}
+
+// dialectDataTypeMaps are the mappings of types specific to a database type,
+// keyed by the type name reported by its driver.
+var dialectDataTypeMaps = map[consts.DataBaseType]dataTypeMap{
+	consts.Postgres: {
+		"int2":        goType("int16"),
+		"int4":        goType("int32"),
+		"int8":        goType("int64"),
+		"float4":      goType("float32"),
+		"float8":      goType("float64"),
+		"timestamp":   goType("time.Time"),
+		"timestamptz": goType("time.Time"),
+		"date":        goType("time.Time"),
+		"time":        goType("string"), // pgx returns time of day as text
+		"timetz":      goType("string"),
+		"uuid":        goType("string"),
+		"json":        goType("datatypes.JSON"),
+		"jsonb":       goType("datatypes.JSON"),
+		"bytea":       goType("[]byte"),
+		"bpchar":      goType("string"),
+
+		"_int2":        goType("pgtype.Int2Array"),
+		"_int4":        goType("pgtype.Int4Array"),
+		"_int8":        goType("pgtype.Int8Array"),
+		"_float4":      goType("pgtype.Float4Array"),
+		"_float8":      goType("pgtype.Float8Array"),
+		"_numeric":     goType("pgtype.NumericArray"),
+		"_bool":        goType("pgtype.BoolArray"),
+		"_text":        goType("pgtype.TextArray"),
+		"_varchar":     goType("pgtype.VarcharArray"),
+		"_bpchar":      goType("pgtype.BPCharArray"),
+		"_uuid":        goType("pgtype.UUIDArray"),
+		"_bytea":       goType("pgtype.ByteaArray"),
+		"_date":        goType("pgtype.DateArray"),
+		"_timestamp":   goType("pgtype.TimestampArray"),
+		"_timestamptz": goType("pgtype.TimestamptzArray"),
+		"_json":        goType("pgtype.JSONArray"),
+		"_jsonb":       goType("pgtype.JSONBArray"),
+	},
+	consts.Sqlite: {
+		// integers of sqlite are always 64-bit
+		"integer": goType("int64"),
+		"int":     goType("int64"),
+		"bigint":  goType("int64"),
+		"int2":    goType("int64"),
+		"int8":    goType("int64"),
+		"double":  goType("float64"),
+		"float":   goType("float64"),
+	},
+	consts.SQLServer: {
+		"bit":              goType("bool"),
+		"tinyint":          goType("uint8"),
+		"smallint":         goType("int16"),
+		"int":              goType("int32"),
+		"bigint":           goType("int64"),
+		"real":             goType("float32"),
+		"float":            goType("float64"),
+		"money":            goType("float64"),
+		"smallmoney":       goType("float64"),
+		"datetime":         goType("time.Time"),
+		"datetime2":        goType("time.Time"),
+		"smalldatetime":    goType("time.Time"),
+		"datetimeoffset":   goType("time.Time"),
+		"date":             goType("time.Time"),
+		"time":             goType("time.Time"),
+		"uniqueidentifier": goType("mssql.UniqueIdentifier"),
+		"image":            goType("[]byte"),
+	},
+}
+
+// dialectImportPkgPaths are the packages referred by dialectDataTypeMaps,
+// unused imports are removed from the generated files by gorm gen.
+var dialectImportPkgPaths = map[consts.DataBaseType][]string{
+	consts.Postgres:  {"github.com/jackc/pgtype"},
+	consts.SQLServer: {`mssql "github.com/microsoft/go-mssqldb"`},
+}
+
+// newDataTypeMap merges baseDataTypeMap with the mappings of dbType.
+// Keys are in lower case, matching the type names reported by genColumnType.
+func newDataTypeMap(dbType consts.DataBaseType) dataTypeMap {
+	m := make(dataTypeMap)
+	for _, typeMap := range []dataTypeMap{baseDataTypeMap, dialectDataTypeMaps[dbType]} {
+		for name, mapping := range typeMap {
+			m[name] = mapping
+		}
+	}
+	return m
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+)
+
+// genDialector wraps the dialector of the database, so that gorm gen reads
+// tables through genMigrator.
+type genDialector struct {
+	gorm.Dialector
+}
+
+func (d genDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return genMigrator{Migrator: d.Dialector.Migrator(db), db: db}
+}
+
+// genMigrator fixes the column metadata reported by the migrator of the database.
+type genMigrator struct {
+	gorm.Migrator
+	db *gorm.DB
+}
+
+func (m genMigrator) ColumnTypes(value interface{}) ([]gorm.ColumnType, error) {
+	columnTypes, err := m.Migrator.ColumnTypes(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var pragmaColumns map[string]sqliteColumn
+	if table, ok := value.(string); ok && m.db.Dialector.Name() == sqliteDialectorName {
+		if pragmaColumns, err = sqliteTableInfo(m.db, table); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, ct := range columnTypes {
+		c := &genColumnType{baseColumnType: ct}
+		if pc, ok := pragmaColumns[ct.Name()]; ok {
+			c.sqlite = &pc
+		}
+		columnTypes[i] = c
+	}
+	return columnTypes, nil
+}
+
+func (m genMigrator) GetIndexes(value interface{}) ([]gorm.Index, error) {
+	if table, ok := value.(string); ok && m.db.Dialector.Name() == sqliteDialectorName {
+		return sqliteIndexes(m.db, table)
+	}
+	return m.Migrator.GetIndexes(value)
+}
+
+// genColumnType reports the type name in lower case, since custom data type
+// mappings of gorm gen are looked up by the exact type name, and sqlite reports
+// it as written in the DDL.
+type genColumnType struct {
+	baseColumnType
+	sqlite *sqliteColumn
+}
+
+// baseColumnType names the embedded field, which would clash with method ColumnType otherwise.
+type baseColumnType = gorm.ColumnType
+
+func (c *genColumnType) DatabaseTypeName() string {
+	if c.sqlite != nil {
+		return c.sqlite.typeName()
+	}
+	return strings.ToLower(c.baseColumnType.DatabaseTypeName())
+}
+
+func (c *genColumnType) PrimaryKey() (isPrimaryKey bool, ok bool) {
+	if c.sqlite != nil {
+		return c.sqlite.PK > 0, true
+	}
+	return c.baseColumnType.PrimaryKey()
+}
+
+func (c *genColumnType) Nullable() (nullable bool, ok bool) {
+	if c.sqlite != nil {
+		return !c.sqlite.NotNull && c.sqlite.PK == 0, true
+	}
+	return c.baseColumnType.Nullable()
+}
+
+const sqliteDialectorName = "sqlite"
+
+// sqliteColumn is a row of PRAGMA table_info. The DDL parser of the sqlite
+// migrator misses table constraints such as composite primary keys, reports
+// columns without NOT NULL as not nullable, and cuts digits off type names.
+type sqliteColumn struct {
+	Name    string
+	Type    string // declared type, e.g. VARCHAR(64)
+	NotNull bool
+	PK      int // position in the primary key, 0 if not a part of it
+}
+
+// typeName returns the declared type in lower case without its size.
+func (c *sqliteColumn) typeName() string {
+	name := c.Type
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func sqliteTableInfo(db *gorm.DB, table string) (map[string]sqliteColumn, error) {
+	var columns []sqliteColumn
+	err := db.Raw(`SELECT name, type, "notnull" AS not_null, pk FROM pragma_table_info(?)`, table).Scan(&columns).Error
+	if err != nil {
+		return nil, fmt.Errorf("read sqlite table info of %s fail: %w", table, err)
+	}
+
+	columnMap := make(map[string]sqliteColumn, len(columns))
+	for _, c := range columns {
+		columnMap[c.Name] = c
+	}
+	return columnMap, nil
+}
+
+func sqliteIndexes(db *gorm.DB, table string) ([]gorm.Index, error) {
+	var indexList []struct {
+		Name   string
+		Unique bool
+		Origin string // c: CREATE INDEX, u: UNIQUE constraint, pk: PRIMARY KEY
+	}
+	err := db.Raw(`SELECT name, "unique", origin FROM pragma_index_list(?)`, table).Scan(&indexList).Error
+	if err != nil {
+		return nil, fmt.Errorf("read sqlite index list of %s fail: %w", table, err)
+	}
+
+	indexes := make([]gorm.Index, 0, len(indexList))
+	for _, idx := range indexList {
+		var columns []string
+		err = db.Raw(`SELECT name FROM pragma_index_info(?) ORDER BY seqno`, idx.Name).Scan(&columns).Error
+		if err != nil {
+			return nil, fmt.Errorf("read sqlite index info of %s fail: %w", idx.Name, err)
+		}
+		index := &migrator.Index{
+			TableName:  table,
+			NameValue:  idx.Name,
+			ColumnList: columns,
+		}
+		index.PrimaryKeyValue.Bool, index.PrimaryKeyValue.Valid = idx.Origin == "pk", true
+		index.UniqueValue.Bool, index.UniqueValue.Valid = idx.Unique, true
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
@@ -18,6 +18,8 @@ package model
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/cloudwego/cwgo/config"
@@ -28,8 +30,16 @@ import (
 )
 
 func Model(c *config.ModelArgument) error {
+	dsn := c.DSN
+	if c.Type == string(consts.Postgres) && c.Schema != "" {
+		var err error
+		if dsn, err = withSearchPath(dsn, c.Schema); err != nil {
+			return err
+		}
+	}
+
 	dialector := config.OpenTypeFuncMap[consts.DataBaseType(c.Type)]
-	db, err := gorm.Open(dialector(c.DSN))
+	db, err := gorm.Open(genDialector{Dialector: dialector(dsn)})
 	if err != nil {
 		return err
 	}
@@ -43,18 +53,31 @@ func Model(c *config.ModelArgument) error {
 		FieldSignable:     c.FieldSignable,
 		FieldWithIndexTag: c.FieldWithIndexTag,
 	}
+	genConfig.WithDataTypeMap(newDataTypeMap(consts.DataBaseType(c.Type)))
+	genConfig.WithImportPkgPath(dialectImportPkgPaths[consts.DataBaseType(c.Type)]...)
+
+	excludeTables := make(map[string]struct{}, len(c.ExcludeTables))
+	for _, table := range c.ExcludeTables {
+		excludeTables[table] = struct{}{}
+	}
+	if skipTables, ok := skipTablesFuncMap[consts.DataBaseType(c.Type)]; ok && len(c.Tables) == 0 {
+		tables, err := skipTables(db, c)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			excludeTables[table] = struct{}{}
+		}
+	}
 
-	if len(c.ExcludeTables) > 0 || c.Type == string(consts.Sqlite) {
+	isSystemTable, hasSystemTables := systemTableFuncMap[consts.DataBaseType(c.Type)]
+	if len(excludeTables) > 0 || hasSystemTables {
 		genConfig.WithTableNameStrategy(func(tableName string) (targetTableName string) {
-			if c.Type == string(consts.Sqlite) && strings.HasPrefix(tableName, "sqlite") {
+			if hasSystemTables && isSystemTable(tableName) {
 				return ""
 			}
-			if len(c.ExcludeTables) > 0 {
-				for _, table := range c.ExcludeTables {
-					if tableName == table {
-						return ""
-					}
-				}
+			if _, ok := excludeTables[tableName]; ok {
+				return ""
 			}
 			return tableName
 		})
@@ -77,6 +100,45 @@ func Model(c *config.ModelArgument) error {
 	return nil
 }
 
+// systemTableFuncMap reports the tables maintained by the database itself,
+// which are returned by the migrator but should never be generated as models.
+var systemTableFuncMap = map[consts.DataBaseType]func(tableName string) bool{
+	consts.Sqlite: func(tableName string) bool {
+		return strings.HasPrefix(tableName, "sqlite")
+	},
+	consts.SQLServer: func(tableName string) bool {
+		return tableName == "sysdiagrams"
+	},
+}
+
+// skipTablesFuncMap queries the tables returned by the migrator which are not
+// generated unless they are listed by --tables explicitly.
+var skipTablesFuncMap = map[consts.DataBaseType]func(db *gorm.DB, c *config.ModelArgument) ([]string, error){
+	consts.Postgres: func(db *gorm.DB, c *config.ModelArgument) (tables []string, err error) {
+		if c.WithPartitions {
+			return nil, nil
+		}
+		// partitions are listed as base tables next to their parent,
+		// children of plain table inheritance are kept
+		err = db.Raw("SELECT c.relname FROM pg_catalog.pg_class c " +
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace " +
+			"WHERE c.relispartition AND n.nspname = CURRENT_SCHEMA()").Scan(&tables).Error
+		if err != nil {
+			return nil, fmt.Errorf("query postgres partitions fail: %w", err)
+		}
+		return tables, nil
+	},
+	consts.SQLServer: func(db *gorm.DB, c *config.ModelArgument) (tables []string, err error) {
+		// views are listed in INFORMATION_SCHEMA.tables too
+		err = db.Raw("SELECT table_name FROM INFORMATION_SCHEMA.tables WHERE table_catalog = ? AND table_type = ?",
+			db.Migrator().CurrentDatabase(), "VIEW").Scan(&tables).Error
+		if err != nil {
+			return nil, fmt.Errorf("query sqlserver views fail: %w", err)
+		}
+		return tables, nil
+	},
+}
+
 func genModels(g *gen.Generator, db *gorm.DB, tables []string) (models []interface{}, err error) {
 	var tablesNameList []string
 	if len(tables) == 0 {
@@ -94,3 +156,49 @@ func genModels(g *gen.Generator, db *gorm.DB, tables []string) (models []interfa
 	}
 	return models, nil
 }
+
+// withSearchPath sets the postgres search_path of dsn to schema, so that the
+// migrator introspects tables of schema instead of the default one.
+// Both the URL and the keyword/value dsn formats are supported, and an existing
+// search_path of dsn is replaced.
+func withSearchPath(dsn, schema string) (string, error) {
+	searchPath := quoteIdent(schema)
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("parse postgres dsn fail: %w", err)
+		}
+		query := u.Query()
+		query.Set("search_path", searchPath)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	var pairs []string
+	for _, m := range dsnPairReg.FindAllStringSubmatch(dsn, -1) {
+		if m[1] != "search_path" {
+			pairs = append(pairs, m[0])
+		}
+	}
+	pairs = append(pairs, "search_path="+quoteDSNValue(searchPath))
+	return strings.Join(pairs, " "), nil
+}
+
+// dsnPairReg matches a key=value pair of a keyword/value dsn, the value may be single quoted.
+var dsnPairReg = regexp.MustCompile(`(\w+)\s*=\s*('(?:[^'\\]|\\.)*'|\S*)`)
+
+var plainIdentReg = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// quoteIdent quotes schema as a postgres identifier when needed,
+// so that search_path takes it as one case-sensitive schema name.
+func quoteIdent(schema string) string {
+	if plainIdentReg.MatchString(schema) {
+		return schema
+	}
+	return `"` + strings.ReplaceAll(schema, `"`, `""`) + `"`
+}
+
+// quoteDSNValue quotes value for the keyword/value dsn of libpq.
+func quoteDSNValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/cwgo/config"
+	"github.com/cloudwego/cwgo/pkg/consts"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestPostgres generates models from testdata/postgres.sql, and round-trips a row
+// through the generated code. It needs a postgres to connect, e.g.
+//
+//	docker run -d -p 5432:5432 -e POSTGRES_PASSWORD=gorm postgres:15
+//	CWGO_TEST_POSTGRES_DSN="host=localhost user=postgres password=gorm sslmode=disable" go test ./pkg/model/
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("CWGO_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("CWGO_TEST_POSTGRES_DSN is not set")
+	}
+
+	const schema = "cwgo_test"
+	db, err := gorm.Open(postgres.Open(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropSchema := func() {
+		if err := db.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE").Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	dropSchema()
+	t.Cleanup(dropSchema)
+	if err = db.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	schemaDSN, err := withSearchPath(dsn, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaDB, err := gorm.Open(postgres.Open(schemaDSN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	execFixture(t, schemaDB, "postgres.sql")
+
+	t.Run("crud", func(t *testing.T) {
+		dir := newCRUDModule(t)
+		err := Model(&config.ModelArgument{
+			DSN:               dsn,
+			Type:              string(consts.Postgres),
+			Schema:            schema,
+			OutPath:           filepath.Join(dir, "query"),
+			OutFile:           consts.DefaultDbOutFile,
+			FieldNullable:     true,
+			FieldWithIndexTag: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		models := parseModels(t, filepath.Join(dir, "model"))
+		accounts := models["accounts"]
+		assertField(t, accounts, "TenantID", "int32", "primaryKey")
+		assertField(t, accounts, "ID", "string", "primaryKey")
+		assertField(t, accounts, "Profile", "datatypes.JSON", "not null")
+		assertField(t, accounts, "Balance", "float64", "not null")
+		assertField(t, accounts, "CreatedAt", "time.Time", "not null")
+		assertField(t, accounts, "Tags", "*pgtype.TextArray", "!not null")
+		assertField(t, accounts, "Scores", "*pgtype.Int4Array", "!not null")
+		assertField(t, accounts, "Note", "*string", "!not null")
+		for _, table := range []string{"events", "animals", "dogs"} {
+			if _, ok := models[table]; !ok {
+				t.Errorf("table %s is not generated", table)
+			}
+		}
+		if _, ok := models["events_2024"]; ok {
+			t.Errorf("partition events_2024 is generated without --partitions")
+		}
+
+		content, err := os.ReadFile(filepath.Join("testdata", "postgres_crud", "crud_test.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, "crud_test.go"), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(consts.Go, "test", "./...")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "CWGO_TEST_DSN="+schemaDSN)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("generated code failed: %v\n%s", err, out)
+		}
+	})
+
+	t.Run("partitions", func(t *testing.T) {
+		dir := t.TempDir()
+		err := Model(&config.ModelArgument{
+			DSN:            dsn,
+			Type:           string(consts.Postgres),
+			Schema:         schema,
+			WithPartitions: true,
+			OnlyModel:      true,
+			OutPath:        filepath.Join(dir, "query"),
+			OutFile:        consts.DefaultDbOutFile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := parseModels(t, filepath.Join(dir, "model"))["events_2024"]; !ok {
+			t.Errorf("partition events_2024 is not generated with --partitions")
+		}
+	})
+}
+
+// newCRUDModule creates a module for the generated code, which requires the
+// same versions of gorm and drivers as cwgo.
+func newCRUDModule(t *testing.T) string {
+	dir := t.TempDir()
+	out, err := exec.Command(consts.Go, "list", "-m", "-f", "{{.Path}} {{.Version}}",
+		"gorm.io/gorm", "gorm.io/gen", "gorm.io/datatypes", "gorm.io/plugin/dbresolver",
+		"gorm.io/driver/postgres", "github.com/jackc/pgtype").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod := "module cwgotest\n\ngo 1.18\n\nrequire (\n"
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		goMod += "\t" + line + "\n"
+	}
+	goMod += ")\n"
+	if err = os.WriteFile(filepath.Join(dir, consts.GoMod), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join("..", "..", "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// let go resolve the requirements of the generated code
+	t.Setenv("GOFLAGS", "-mod=mod")
+	return dir
+}
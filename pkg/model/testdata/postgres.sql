@@ -0,0 +1,29 @@
+-- composite primary key, nullable columns and postgres specific types
+CREATE TABLE accounts (
+    tenant_id  int4          NOT NULL,
+    id         uuid          NOT NULL,
+    profile    jsonb         NOT NULL,
+    balance    numeric(12,2) NOT NULL,
+    created_at timestamptz   NOT NULL,
+    tags       text[],
+    scores     int4[],
+    note       text,
+    PRIMARY KEY (tenant_id, id)
+);
+
+-- partitioned table, its partition is skipped unless --partitions is set
+CREATE TABLE events (
+    id         int8        NOT NULL,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+
+-- children of plain table inheritance are always generated
+CREATE TABLE animals (
+    id   int8 PRIMARY KEY,
+    name text
+);
+CREATE TABLE dogs (
+    breed text
+) INHERITS (animals);
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2022 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package crud is copied next to the code generated from ../postgres.sql,
+// and round-trips a row through the generated model and query.
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"cwgotest/model"
+	"cwgotest/query"
+
+	"github.com/jackc/pgtype"
+	"gorm.io/datatypes"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestCRUD(t *testing.T) {
+	db, err := gorm.Open(postgres.Open(os.Getenv("CWGO_TEST_DSN")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	q := query.Use(db).Account
+
+	var tags pgtype.TextArray
+	if err = tags.Set([]string{"a", "b c"}); err != nil {
+		t.Fatal(err)
+	}
+	note := "it's a note"
+	account := &model.Account{
+		TenantID:  1,
+		ID:        "6f1f5c52-9d1e-4c59-8d3b-2f3c8c1b7a10",
+		Profile:   datatypes.JSON(`{"level": 1}`),
+		Balance:   12.34,
+		CreatedAt: time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC),
+		Tags:      &tags,
+		Note:      &note,
+	}
+	if err = q.WithContext(ctx).Create(account); err != nil {
+		t.Fatal(err)
+	}
+
+	cond := q.WithContext(ctx).Where(q.TenantID.Eq(account.TenantID), q.ID.Eq(account.ID))
+	got, err := cond.First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Balance != account.Balance || !got.CreatedAt.Equal(account.CreatedAt) || got.Note == nil || *got.Note != note {
+		t.Errorf("got %+v, want %+v", got, account)
+	}
+	if got.Scores != nil {
+		t.Errorf("scores should be null, got %+v", got.Scores)
+	}
+	var gotTags []string
+	if err = got.Tags.AssignTo(&gotTags); err != nil || len(gotTags) != 2 || gotTags[1] != "b c" {
+		t.Errorf("got tags %v, err: %v", gotTags, err)
+	}
+	var profile map[string]int
+	if err = json.Unmarshal(got.Profile, &profile); err != nil || profile["level"] != 1 {
+		t.Errorf("got profile %s, err: %v", got.Profile, err)
+	}
+
+	if _, err = cond.Update(q.Balance, 56.78); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = cond.First(); err != nil || got.Balance != 56.78 {
+		t.Errorf("got balance %v after update, err: %v", got.Balance, err)
+	}
+
+	if _, err = cond.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := cond.Count(); err != nil || count != 0 {
+		t.Errorf("got %d rows after delete, err: %v", count, err)
+	}
+}
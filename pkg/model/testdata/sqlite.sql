@@ -0,0 +1,20 @@
+-- composite primary key and nullable columns, types are written in mixed case on purpose
+CREATE TABLE orders (
+    tenant_id  INTEGER  NOT NULL,
+    order_id   BIGINT   NOT NULL,
+    Qty        Integer  NOT NULL,
+    note       TEXT     NULL,
+    price      NUMERIC,
+    paid       BOOLEAN,
+    amount     REAL,
+    weight     INT8,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (tenant_id, order_id)
+);
+CREATE INDEX idx_orders_created_at ON orders (created_at);
+
+CREATE TABLE users (
+    id       INTEGER PRIMARY KEY AUTOINCREMENT,
+    email    TEXT NOT NULL UNIQUE,
+    nickname VARCHAR(64)
+);
@@ -0,0 +1,15 @@
+-- composite primary key, nullable columns and sqlserver specific types
+CREATE TABLE orders (
+    tenant_id  INT              NOT NULL,
+    order_id   BIGINT           NOT NULL,
+    paid       BIT              NOT NULL,
+    price      MONEY            NULL,
+    ref        UNIQUEIDENTIFIER NULL,
+    created_at DATETIME2        NOT NULL,
+    updated_at DATETIMEOFFSET   NULL,
+    note       NVARCHAR(255)    NULL,
+    PRIMARY KEY (tenant_id, order_id)
+);
+
+-- views are not generated as table models
+CREATE VIEW paid_orders AS SELECT tenant_id, order_id FROM orders WHERE paid = 1;
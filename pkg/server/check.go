@@ -26,6 +26,7 @@ import (
 	"github.com/cloudwego/cwgo/config"
 	"github.com/cloudwego/cwgo/pkg/common/utils"
 	"github.com/cloudwego/cwgo/pkg/consts"
+	"github.com/cloudwego/cwgo/pkg/deploy"
 )
 
 func check(sa *config.ServerArgument) error {
@@ -41,10 +42,28 @@ func check(sa *config.ServerArgument) error {
 		return errors.New("unsupported registry")
 	}
 
+	if sa.Deploy != "" && sa.Deploy != consts.Docker && sa.Deploy != consts.K8s {
+		return errors.New("unsupported deploy type")
+	}
+
+	if sa.Deploy == consts.K8s && (sa.Replicas < 1 || sa.MaxReplicas < sa.Replicas) {
+		return errors.New("replicas must be positive and not greater than max_replicas")
+	}
+
+	for _, m := range sa.DeployWith {
+		if m != string(consts.MySQL) && m != consts.Redis {
+			return fmt.Errorf("unsupported deploy middleware: %s", m)
+		}
+	}
+
 	if sa.Service == "" {
 		return errors.New("must specify service name")
 	}
 
+	if sa.Deploy != "" && deploy.AppName(sa.Service) == "" {
+		return fmt.Errorf("service name %s can not be converted to a DNS-1123 name used by deploy, it must contain ASCII letters or digits", sa.Service)
+	}
+
 	// handle cwd and output dir
 	dir, err := os.Getwd()
 	if err != nil {
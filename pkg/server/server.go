@@ -27,6 +27,7 @@ import (
 	"github.com/cloudwego/cwgo/pkg/common/kx_registry"
 	"github.com/cloudwego/cwgo/pkg/common/utils"
 	"github.com/cloudwego/cwgo/pkg/consts"
+	"github.com/cloudwego/cwgo/pkg/deploy"
 	"github.com/cloudwego/hertz/cmd/hz/app"
 	hzConfig "github.com/cloudwego/hertz/cmd/hz/config"
 	"github.com/cloudwego/hertz/cmd/hz/meta"
@@ -169,5 +170,8 @@ func Server(c *config.ServerArgument) error {
 		utils.ReplaceThriftVersion()
 	}
 
+	if c.Deploy != "" {
+		return deploy.Deploy(c)
+	}
 	return nil
 }
@@ -33,18 +33,25 @@ var kitexTpl embed.FS
 //go:embed hertz
 var hertzTpl embed.FS
 
+//go:embed deploy
+var deployTpl embed.FS
+
 var (
-	KitexDir = path.Join(os.TempDir(), consts.Kitex)
-	HertzDir = path.Join(os.TempDir(), consts.Hertz)
+	KitexDir  = path.Join(os.TempDir(), consts.Kitex)
+	HertzDir  = path.Join(os.TempDir(), consts.Hertz)
+	DeployDir = path.Join(os.TempDir(), consts.Deploy)
 )
 
 func Init() {
 	os.RemoveAll(KitexDir)
 	os.RemoveAll(HertzDir)
+	os.RemoveAll(DeployDir)
 	os.Mkdir(KitexDir, 0o755)
 	os.Mkdir(HertzDir, 0o755)
+	os.Mkdir(DeployDir, 0o755)
 	initDir(kitexTpl, consts.Kitex, KitexDir)
 	initDir(hertzTpl, consts.Hertz, HertzDir)
+	initDir(deployTpl, consts.Deploy, DeployDir)
 }
 
 func initDir(fs embed.FS, srcDir, dstDir string) {